@@ -1,22 +1,26 @@
 /*
-	take a pdf file and produce a directory of jpeg images
+	take a pdf file and produce a directory of images
 	$ pdfToImg -f myPDF -o myDir -s 0 -e 10 -a=true
+
+	-f also accepts "-" to read the pdf from stdin, or an http(s):// URL to download it first
 */
 
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"image/jpeg"
+	"io"
 	"log"
-	"math"
+	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/gen2brain/go-fitz"
+	"github.com/cheggaaa/pb/v3"
+
+	"github.com/AndrewsPrivateStash/pdf-to-image/pkg/pdftoimage"
 )
 
 func main() {
@@ -26,120 +30,66 @@ func main() {
 	endPgF := flag.Int("e", -1, "the ending page to convert (-1 is all)")
 	appendF := flag.Bool("a", false, "add files to directory without removing old ones")
 	chunkSizeF := flag.Int("c", 100, "the chunksize to process before unloading the doc (avoids mem-leak)")
+	workersF := flag.Int("w", 1, "number of concurrent render workers (1-16)")
+	formatF := flag.String("format", "jpg", "output image format: jpg, png, tiff, webp")
+	qualityF := flag.Int("quality", 100, "output quality, 1-100 (jpg and webp only)")
+	dpiF := flag.Float64("dpi", pdftoimage.DefaultDPI, "the DPI to render pages at")
+	resumeF := flag.Bool("resume", false, "resume an interrupted conversion using the manifest left in the output directory")
 
 	flag.Parse()
 	if flag.NFlag() < 1 {
 		log.Fatal("for options: $ pdfToImg -h\nrequires at least a pdf file path.\n$ pdfToImg -f my_pdf.pdf")
 	}
 
-	// make output folder
-	if err := os.MkdirAll(*outDirF, 0755); err != nil {
-		log.Fatal(err)
-	}
-
-	// clean up output folder if already exists
-	if !*appendF {
-		log.Printf("removing files in %s\n", *outDirF)
-		if _, err := os.Stat(*outDirF); !os.IsNotExist(err) {
-			err = removeAllFiles(*outDirF)
-			checkError(err)
-		}
-	}
-
-	doc, err := fitz.New(*inFileF)
-	checkError(err)
-	totalPages := doc.NumPage()
-	doc.Close()
-
-	// Determine bounds
 	startPage := 0
 	if *startPgF > 0 && *startPgF <= *endPgF {
 		startPage = *startPgF - 1
 	}
 
-	endPage := *endPgF
-	if *endPgF < 0 || *endPgF > totalPages {
-		endPage = totalPages
+	inFile := *inFileF
+	var reader io.Reader
+	switch {
+	case inFile == "-":
+		reader = os.Stdin
+	case strings.HasPrefix(inFile, "http://"), strings.HasPrefix(inFile, "https://"):
+		tmp, err := downloadToTemp(inFile)
+		checkError(err)
+		defer os.Remove(tmp)
+		inFile = tmp
 	}
 
-	// process chunks
-	startTime := time.Now()
-	log.Printf("processing %d page(s), in chunks of: %d\n", endPage-startPage, *chunkSizeF)
-	remPages := endPage - startPage
-	curStart, curEnd := startPage, intMin(startPage+remPages, startPage+*chunkSizeF, totalPages)
-	count := 0
-	for remPages > 0 {
-		count = processChunk(curStart, curEnd, *inFileF, *outDirF, count, endPage-startPage)
-		remPages -= curEnd - curStart
-		curStart, curEnd = curEnd, intMin(curEnd+*chunkSizeF, totalPages)
+	opts := pdftoimage.ConvertOptions{
+		InFile:    inFile,
+		Reader:    reader,
+		StartPage: startPage,
+		EndPage:   *endPgF,
+		Format:    *formatF,
+		Quality:   *qualityF,
+		DPI:       *dpiF,
+		OutDir:    *outDirF,
+		Append:    *appendF,
+		Workers:   *workersF,
+		ChunkSize: *chunkSizeF,
+		Resume:    *resumeF,
 	}
-	fmt.Printf("\nconversion took: %v\n", time.Since(startTime))
-	fmt.Println("done! \xf0\x9f\x99\x8c")
-}
 
-func processChunk(start int, end int, f string, opath string, cnt int, tot int) int {
-	doc, err := fitz.New(f)
+	startTime := time.Now()
+	task, err := pdftoimage.Convert(context.Background(), opts)
 	checkError(err)
-	defer doc.Close()
-
-	const pollFreq = 5
-
-	// Extract pages as images
-	count := cnt
-	for n := start; n < end; n++ {
 
-		img, err := doc.Image(n)
-		checkError(err)
-
-		f, err := os.Create(filepath.Join(opath, fmt.Sprintf("%03d.jpg", n+1)))
-		checkError(err)
-
-		err = jpeg.Encode(f, img, &jpeg.Options{Quality: 100})
-		checkError(err)
+	log.Printf("processing %d page(s), in chunks of: %d, with %d worker(s)\n", task.Total(), *chunkSizeF, task.Workers())
 
-		if count%pollFreq == 0 {
-			logProgress(tot, count)
+	pr := newProgressReporter(task.Total(), task.Workers())
+	go func() {
+		for ev := range task.Progress() {
+			pr.Report(ev.Worker)
 		}
+		pr.Close()
+	}()
 
-		f.Close()
-		count++
-	}
-	logProgress(tot, count)
-	return count
-}
-
-func removeAllFiles(dir string) error {
-	d, err := os.Open(dir)
-	if err != nil {
-		return err
-	}
-	defer d.Close()
-	names, err := d.Readdirnames(-1)
-	if err != nil {
-		return err
-	}
-	for _, name := range names {
-		err = os.RemoveAll(filepath.Join(dir, name))
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func logProgress(tot int, cur int) {
-
-	const SYMBOL_WIDTH = 20
-	const SYMBOL = "#"
-
-	progress := float64(cur) / float64(tot)
-	outStr := fmt.Sprintf("\rthrough pg: %d\t[", cur)
-
-	symCnt := int(math.Ceil(SYMBOL_WIDTH * progress))
-	outStr += strings.Repeat(SYMBOL, symCnt)
-	outStr += strings.Repeat(" ", SYMBOL_WIDTH-symCnt) + "]"
-	outStr += string(" ") + fmt.Sprintf("%.1f%%", progress*100)
-	fmt.Printf("%s", outStr)
+	checkError(task.Wait())
+	fmt.Printf("\nconversion took: %v\n", time.Since(startTime))
+	fmt.Println("done! \xf0\x9f\x99\x8c")
 }
 
 func checkError(e error) {
@@ -148,21 +98,33 @@ func checkError(e error) {
 	}
 }
 
-func intMin(vals ...int) int {
-	if len(vals) == 0 { //should not happen, break don't handle
-		panic("no arguments passed to 'min'")
+// downloadToTemp fetches url into a temp file, showing a progress bar while
+// it downloads, and returns the temp file's path. The caller is responsible
+// for removing it.
+func downloadToTemp(url string) (string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download %s: %s", url, resp.Status)
 	}
 
-	if len(vals) == 1 {
-		return vals[0]
+	tmp, err := os.CreateTemp("", "pdftoimage-*.pdf")
+	if err != nil {
+		return "", err
 	}
+	defer tmp.Close()
 
-	best := vals[0]
-	for _, val := range vals[1:] {
-		if val < best {
-			best = val
-		}
+	bar := pb.Full.Start64(resp.ContentLength)
+	bar.Set(pb.Bytes, true)
+	defer bar.Finish()
+
+	if _, err := io.Copy(tmp, bar.NewProxyReader(resp.Body)); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
 	}
 
-	return best
+	return tmp.Name(), nil
 }
@@ -0,0 +1,71 @@
+package pdftoimage
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/chai2010/webp"
+	"golang.org/x/image/tiff"
+)
+
+// encoder writes a rendered page image to w in a single output format.
+type encoder interface {
+	Encode(w io.Writer, img image.Image) error
+	Ext() string
+}
+
+// newEncoder returns the encoder for the named format ("jpg", "png", "tiff",
+// "webp"), applying quality where the format supports it.
+func newEncoder(format string, quality int) (encoder, error) {
+	switch format {
+	case "jpg", "jpeg":
+		return jpgEncoder{quality: quality}, nil
+	case "png":
+		return pngEncoder{}, nil
+	case "tiff":
+		return tiffEncoder{}, nil
+	case "webp":
+		return webpEncoder{quality: float32(quality)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+type jpgEncoder struct {
+	quality int
+}
+
+func (e jpgEncoder) Encode(w io.Writer, img image.Image) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: e.quality})
+}
+
+func (e jpgEncoder) Ext() string { return "jpg" }
+
+type pngEncoder struct{}
+
+func (e pngEncoder) Encode(w io.Writer, img image.Image) error {
+	return png.Encode(w, img)
+}
+
+func (e pngEncoder) Ext() string { return "png" }
+
+type tiffEncoder struct{}
+
+func (e tiffEncoder) Encode(w io.Writer, img image.Image) error {
+	return tiff.Encode(w, img, nil)
+}
+
+func (e tiffEncoder) Ext() string { return "tiff" }
+
+type webpEncoder struct {
+	quality float32
+}
+
+func (e webpEncoder) Encode(w io.Writer, img image.Image) error {
+	return webp.Encode(w, img, &webp.Options{Quality: e.quality})
+}
+
+func (e webpEncoder) Ext() string { return "webp" }
@@ -0,0 +1,33 @@
+package pdftoimage
+
+import "testing"
+
+func TestNewEncoder(t *testing.T) {
+	cases := []struct {
+		format  string
+		wantExt string
+	}{
+		{"jpg", "jpg"},
+		{"jpeg", "jpg"},
+		{"png", "png"},
+		{"tiff", "tiff"},
+		{"webp", "webp"},
+	}
+
+	for _, c := range cases {
+		enc, err := newEncoder(c.format, 90)
+		if err != nil {
+			t.Errorf("newEncoder(%q): unexpected error: %v", c.format, err)
+			continue
+		}
+		if got := enc.Ext(); got != c.wantExt {
+			t.Errorf("newEncoder(%q).Ext() = %q, want %q", c.format, got, c.wantExt)
+		}
+	}
+}
+
+func TestNewEncoderUnsupportedFormat(t *testing.T) {
+	if _, err := newEncoder("bmp", 90); err == nil {
+		t.Fatal("newEncoder(\"bmp\"): expected an error, got nil")
+	}
+}
@@ -0,0 +1,63 @@
+package pdftoimage
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func removeAllFiles(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	names, err := d.Readdirnames(-1)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		err = os.RemoveAll(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func intMin(vals ...int) int {
+	if len(vals) == 0 { //should not happen, break don't handle
+		panic("no arguments passed to 'min'")
+	}
+
+	if len(vals) == 1 {
+		return vals[0]
+	}
+
+	best := vals[0]
+	for _, val := range vals[1:] {
+		if val < best {
+			best = val
+		}
+	}
+
+	return best
+}
+
+func intMax(vals ...int) int {
+	if len(vals) == 0 { //should not happen, break don't handle
+		panic("no arguments passed to 'max'")
+	}
+
+	if len(vals) == 1 {
+		return vals[0]
+	}
+
+	best := vals[0]
+	for _, val := range vals[1:] {
+		if val > best {
+			best = val
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,127 @@
+package pdftoimage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+const manifestFileName = "manifest.json"
+
+// manifest records enough state about an in-progress conversion to resume it
+// after a crash or OOM: which input produced it, the render settings used,
+// and which pages have already been written to the output directory.
+type manifest struct {
+	InputPath  string  `json:"input_path"`
+	Sha256     string  `json:"sha256"`
+	DPI        float64 `json:"dpi"`
+	Format     string  `json:"format"`
+	TotalPages int     `json:"total_pages"`
+	Completed  []int   `json:"completed"`
+
+	path string
+	mu   sync.Mutex
+	done map[int]bool
+}
+
+// newManifest builds a fresh manifest. inputLabel and sum identify the
+// source (a file path and its hash, or a descriptive label and the hash of
+// whatever bytes were read from a stream) so a later resume can detect if
+// the input changed.
+func newManifest(inputLabel, sum, opath, format string, dpi float64, totalPages int) (*manifest, error) {
+	return &manifest{
+		InputPath:  inputLabel,
+		Sha256:     sum,
+		DPI:        dpi,
+		Format:     format,
+		TotalPages: totalPages,
+		path:       filepath.Join(opath, manifestFileName),
+		done:       make(map[int]bool),
+	}, nil
+}
+
+// loadManifest reads the manifest left behind in opath and verifies it was
+// produced from the input with hash sum, using the same format and DPI,
+// before allowing a resume.
+func loadManifest(sum, opath, format string, dpi float64) (*manifest, error) {
+	path := filepath.Join(opath, manifestFileName)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var m manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("resume: parsing %s: %w", path, err)
+	}
+
+	if sum != m.Sha256 {
+		return nil, fmt.Errorf("resume: input does not match the one recorded in %s", path)
+	}
+	if m.Format != format {
+		return nil, fmt.Errorf("resume: manifest was recorded with format %q, not %q", m.Format, format)
+	}
+	if m.DPI != dpi {
+		return nil, fmt.Errorf("resume: manifest was recorded with dpi %v, not %v", m.DPI, dpi)
+	}
+
+	m.path = path
+	m.done = make(map[int]bool, len(m.Completed))
+	for _, p := range m.Completed {
+		m.done[p] = true
+	}
+	return &m, nil
+}
+
+// IsDone reports whether page has already been rendered in a prior run.
+func (m *manifest) IsDone(page int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.done[page]
+}
+
+// MarkDone records page as rendered and flushes the manifest to disk so the
+// conversion can resume from here if it's interrupted before finishing.
+func (m *manifest) MarkDone(page int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.done[page] {
+		return nil
+	}
+	m.done[page] = true
+	m.Completed = append(m.Completed, page)
+	sort.Ints(m.Completed)
+	return m.save()
+}
+
+// save writes the manifest to its path, via a temp file and rename so a
+// crash mid-write (the exact failure this feature exists to survive) can
+// never leave a truncated, unparseable manifest.json behind. Callers must
+// hold m.mu.
+func (m *manifest) save() error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), ".manifest-*.json.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	return os.Rename(tmpName, m.path)
+}
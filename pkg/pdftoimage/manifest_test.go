@@ -0,0 +1,77 @@
+package pdftoimage
+
+import "testing"
+
+func TestManifestIsDoneMarkDone(t *testing.T) {
+	dir := t.TempDir()
+
+	m, err := newManifest("in.pdf", "sum", dir, "jpg", 150, 10)
+	if err != nil {
+		t.Fatalf("newManifest: %v", err)
+	}
+
+	if m.IsDone(3) {
+		t.Fatal("IsDone(3) = true before MarkDone")
+	}
+
+	if err := m.MarkDone(3); err != nil {
+		t.Fatalf("MarkDone(3): %v", err)
+	}
+	if !m.IsDone(3) {
+		t.Fatal("IsDone(3) = false after MarkDone")
+	}
+	if m.IsDone(4) {
+		t.Fatal("IsDone(4) = true for a page never marked done")
+	}
+
+	// marking the same page done twice must not duplicate the record.
+	if err := m.MarkDone(3); err != nil {
+		t.Fatalf("MarkDone(3) again: %v", err)
+	}
+	if len(m.Completed) != 1 {
+		t.Fatalf("Completed = %v, want a single entry", m.Completed)
+	}
+}
+
+func TestLoadManifestHashMismatch(t *testing.T) {
+	dir := t.TempDir()
+
+	m, err := newManifest("in.pdf", "sum-a", dir, "jpg", 150, 10)
+	if err != nil {
+		t.Fatalf("newManifest: %v", err)
+	}
+	if err := m.MarkDone(0); err != nil {
+		t.Fatalf("MarkDone: %v", err)
+	}
+
+	if _, err := loadManifest("sum-b", dir, "jpg", 150); err == nil {
+		t.Fatal("loadManifest with mismatched hash: expected an error, got nil")
+	}
+
+	loaded, err := loadManifest("sum-a", dir, "jpg", 150)
+	if err != nil {
+		t.Fatalf("loadManifest with matching hash: %v", err)
+	}
+	if !loaded.IsDone(0) {
+		t.Fatal("loaded manifest lost its completed pages")
+	}
+}
+
+func TestLoadManifestFormatAndDPIMismatch(t *testing.T) {
+	dir := t.TempDir()
+
+	m, err := newManifest("in.pdf", "sum", dir, "jpg", 150, 10)
+	if err != nil {
+		t.Fatalf("newManifest: %v", err)
+	}
+	if err := m.MarkDone(0); err != nil {
+		t.Fatalf("MarkDone: %v", err)
+	}
+
+	if _, err := loadManifest("sum", dir, "png", 150); err == nil {
+		t.Fatal("loadManifest with mismatched format: expected an error, got nil")
+	}
+	if _, err := loadManifest("sum", dir, "jpg", 300); err == nil {
+		t.Fatal("loadManifest with mismatched DPI: expected an error, got nil")
+	}
+}
@@ -0,0 +1,263 @@
+// Package pdftoimage renders PDF pages to image files. It is the library the
+// pdfToImg CLI is a thin wrapper over, so other Go programs can embed the
+// converter instead of shelling out to the binary.
+package pdftoimage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gen2brain/go-fitz"
+)
+
+const (
+	MinWorkers = 1
+	MaxWorkers = 16
+	DefaultDPI = 150
+
+	defaultChunkSize = 100
+)
+
+// ConvertOptions configures a call to Convert.
+type ConvertOptions struct {
+	InFile string    // path to the source PDF
+	Reader io.Reader // if set, takes precedence over InFile as the source; read fully into memory and rendered with fitz.NewFromMemory
+
+	StartPage int // 0-based, inclusive
+	EndPage   int // 0-based, exclusive; < 0 or > total pages means "to the end"
+
+	Format  string
+	Quality int
+	DPI     float64
+
+	OutDir string // directory to write rendered pages into
+	Append bool   // add to OutDir instead of clearing it first
+
+	// WriterFactory, if set, takes precedence over OutDir as the destination
+	// for rendered pages: it's called once per page with a 1-based page
+	// number and must return a writer for that page, which is closed once
+	// the page has been encoded to it. This lets an embedder stream pages
+	// to something other than the filesystem (e.g. S3, an HTTP response).
+	// OutDir is still used for the resume manifest when Resume is set.
+	WriterFactory func(page int) (io.WriteCloser, error)
+
+	Workers   int // clamped to [MinWorkers, MaxWorkers]
+	ChunkSize int // pages to render before unloading and reopening the doc
+
+	Resume bool // skip pages already recorded as done in OutDir's manifest
+}
+
+// Convert starts rendering opts.InFile (or opts.Reader) to images in
+// opts.OutDir and returns immediately with a Task tracking the work. Use
+// Task.Wait to block for completion, Task.Progress to observe page-complete
+// events, and Task.Cancel to stop early via ctx.
+func Convert(ctx context.Context, opts ConvertOptions) (*Task, error) {
+	enc, err := newEncoder(opts.Format, opts.Quality)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.OutDir != "" {
+		if err := os.MkdirAll(opts.OutDir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	src, err := resolveSource(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	newWriter := opts.WriterFactory
+	if newWriter == nil {
+		outDir := opts.OutDir
+		newWriter = func(page int) (io.WriteCloser, error) {
+			return os.Create(filepath.Join(outDir, fmt.Sprintf("%03d.%s", page, enc.Ext())))
+		}
+	}
+
+	workers := intMin(intMax(opts.Workers, MinWorkers), MaxWorkers)
+	dpi := opts.DPI
+	if dpi <= 0 {
+		dpi = DefaultDPI
+	}
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+
+	var mf *manifest
+	if opts.Resume {
+		sum, err := src.sha256()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(filepath.Join(opts.OutDir, manifestFileName)); err == nil {
+			mf, err = loadManifest(sum, opts.OutDir, opts.Format, dpi)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if opts.OutDir != "" && opts.WriterFactory == nil && !opts.Append && !opts.Resume {
+		if err := removeAllFiles(opts.OutDir); err != nil {
+			return nil, err
+		}
+	}
+
+	doc, err := src.open()
+	if err != nil {
+		return nil, err
+	}
+	totalPages := doc.NumPage()
+	doc.Close()
+
+	if opts.Resume {
+		if mf == nil {
+			sum, err := src.sha256()
+			if err != nil {
+				return nil, err
+			}
+			mf, err = newManifest(src.label(), sum, opts.OutDir, opts.Format, dpi, totalPages)
+			if err != nil {
+				return nil, err
+			}
+		} else if mf.TotalPages != totalPages {
+			return nil, fmt.Errorf("resume: manifest recorded %d total page(s), input now has %d", mf.TotalPages, totalPages)
+		}
+	}
+
+	startPage := 0
+	if opts.StartPage > 0 {
+		startPage = opts.StartPage
+	}
+	endPage := opts.EndPage
+	if endPage < 0 || endPage > totalPages {
+		endPage = totalPages
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	t := &Task{
+		events:  make(chan Event, workers),
+		done:    make(chan struct{}),
+		cancel:  cancel,
+		total:   endPage - startPage,
+		workers: workers,
+	}
+
+	go func() {
+		defer close(t.events)
+		defer close(t.done)
+		t.err = run(runCtx, src, newWriter, startPage, endPage, chunkSize, workers, enc, dpi, mf, t.events)
+	}()
+
+	return t, nil
+}
+
+// run drives the chunk loop that used to live in main: render pages in
+// chunk-sized batches, reopening the doc each time to bound memory.
+func run(ctx context.Context, src source, newWriter func(page int) (io.WriteCloser, error), startPage, endPage, chunkSize, workers int, enc encoder, dpi float64, mf *manifest, events chan<- Event) error {
+	remPages := endPage - startPage
+	curStart, curEnd := startPage, intMin(startPage+remPages, startPage+chunkSize, endPage)
+	for remPages > 0 {
+		if err := processChunk(ctx, curStart, curEnd, src, newWriter, workers, enc, dpi, mf, events); err != nil {
+			return err
+		}
+		remPages -= curEnd - curStart
+		curStart, curEnd = curEnd, intMin(curEnd+chunkSize, endPage)
+	}
+	return nil
+}
+
+// processChunk renders pages [start, end) of src using a bounded pool of
+// workers, each holding its own *fitz.Document for the life of the chunk
+// since fitz.Document.Image is not safe for concurrent use. Each worker
+// sends a page-complete Event as it finishes. The first page or doc-open
+// error cancels the chunk so every worker stops pulling new pages instead of
+// continuing to run into the same persistent fault (e.g. a full disk).
+func processChunk(ctx context.Context, start, end int, src source, newWriter func(page int) (io.WriteCloser, error), workers int, enc encoder, dpi float64, mf *manifest, events chan<- Event) error {
+	pages := make(chan int)
+	// sized so no worker can ever block sending an error: each of the
+	// end-start pages can fail once, plus each worker can fail to open its
+	// own doc handle before it pulls any page at all.
+	errs := make(chan error, (end-start)+workers)
+	var wg sync.WaitGroup
+
+	chunkCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+
+			doc, err := src.open()
+			if err != nil {
+				errs <- err
+				cancel()
+				return
+			}
+			defer doc.Close()
+
+			for n := range pages {
+				if mf == nil || !mf.IsDone(n) {
+					if err := renderPage(doc, n, newWriter, enc, dpi, mf); err != nil {
+						errs <- fmt.Errorf("page %d: %w", n+1, err)
+						cancel()
+						continue
+					}
+				}
+				select {
+				case events <- Event{Type: EventPageDone, Worker: worker, Page: n + 1}:
+				case <-ctx.Done():
+				}
+			}
+		}(i)
+	}
+
+feed:
+	for n := start; n < end; n++ {
+		select {
+		case pages <- n:
+		case <-chunkCtx.Done():
+			break feed
+		}
+	}
+	close(pages)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+func renderPage(doc *fitz.Document, n int, newWriter func(page int) (io.WriteCloser, error), enc encoder, dpi float64, mf *manifest) error {
+	img, err := doc.ImageDPI(n, dpi)
+	if err != nil {
+		return err
+	}
+
+	out, err := newWriter(n + 1)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if err := enc.Encode(out, img); err != nil {
+		return err
+	}
+
+	if mf != nil {
+		return mf.MarkDone(n)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package pdftoimage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+
+	"github.com/gen2brain/go-fitz"
+)
+
+// source is a PDF to read pages from, either a path on disk or bytes already
+// in memory (e.g. read from stdin or a URL). Each worker opens its own
+// *fitz.Document from it, since a single Document isn't safe for concurrent
+// use.
+type source struct {
+	path string // also used as a display/manifest label when data is set
+	data []byte
+}
+
+// resolveSource reads opts.Reader into memory when set, preferring
+// fitz.NewFromMemory over writing a temp file; otherwise it opens
+// opts.InFile directly from disk.
+func resolveSource(opts ConvertOptions) (source, error) {
+	if opts.Reader == nil {
+		return source{path: opts.InFile}, nil
+	}
+
+	data, err := io.ReadAll(opts.Reader)
+	if err != nil {
+		return source{}, err
+	}
+	return source{path: opts.InFile, data: data}, nil
+}
+
+// open returns a fresh *fitz.Document handle onto the source.
+func (s source) open() (*fitz.Document, error) {
+	if s.data != nil {
+		return fitz.NewFromMemory(s.data)
+	}
+	return fitz.New(s.path)
+}
+
+// label identifies the source for logs and the resume manifest.
+func (s source) label() string {
+	if s.path != "" {
+		return s.path
+	}
+	return "<stream>"
+}
+
+// sha256 hashes the source's bytes so a resume can detect if the input
+// changed since the manifest was written.
+func (s source) sha256() (string, error) {
+	if s.data != nil {
+		sum := sha256.Sum256(s.data)
+		return hex.EncodeToString(sum[:]), nil
+	}
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,58 @@
+package pdftoimage
+
+import "context"
+
+// EventType identifies what a progress Event reports.
+type EventType int
+
+const (
+	// EventPageDone reports that a single page finished rendering.
+	EventPageDone EventType = iota
+)
+
+// Event is sent on a Task's Progress channel as pages are rendered.
+type Event struct {
+	Type   EventType
+	Worker int
+	Page   int // 1-based page number
+}
+
+// Task is a running conversion started by Convert.
+type Task struct {
+	events  chan Event
+	done    chan struct{}
+	cancel  context.CancelFunc
+	err     error
+	total   int
+	workers int
+}
+
+// Progress returns the channel Convert sends page-complete events on. It is
+// closed when the conversion finishes, whether it succeeded, failed, or was
+// cancelled.
+//
+// Callers must keep draining this channel (e.g. `for range task.Progress()`)
+// until it closes, even if they only care about Wait's return value: render
+// workers send events synchronously and block once the channel's internal
+// buffer fills, so a caller that calls Convert and only calls Wait without
+// ever reading Progress will deadlock the conversion.
+func (t *Task) Progress() <-chan Event { return t.events }
+
+// Wait blocks until the conversion finishes and returns its error, if any.
+func (t *Task) Wait() error {
+	<-t.done
+	return t.err
+}
+
+// Cancel requests that the conversion stop as soon as possible. Workers
+// finish whatever page they're currently rendering before observing it.
+func (t *Task) Cancel() { t.cancel() }
+
+// Total returns the number of pages in the task's page range. With Resume set
+// this includes pages skipped because the manifest already records them as
+// done; a page-complete Event is still sent for each of those.
+func (t *Task) Total() int { return t.total }
+
+// Workers returns the number of render workers the task is using, after
+// ConvertOptions.Workers was clamped to [MinWorkers, MaxWorkers].
+func (t *Task) Workers() int { return t.workers }
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/cheggaaa/pb/v3"
+	"golang.org/x/term"
+)
+
+// progressEvent is emitted by a worker each time it finishes a page.
+type progressEvent struct {
+	worker int
+}
+
+// progressReporter drives a pooled multi-bar display (a total bar plus one
+// bar per worker) while stdout is a TTY, and falls back to the plain
+// carriage-return line the tool always used otherwise. Workers send one
+// event per completed page; a single UI goroutine owns the bars.
+type progressReporter struct {
+	events chan progressEvent
+	done   chan struct{}
+}
+
+// newProgressReporter starts the UI goroutine for a run of tot pages spread
+// across workers concurrent render workers.
+func newProgressReporter(tot, workers int) *progressReporter {
+	r := &progressReporter{
+		events: make(chan progressEvent, workers),
+		done:   make(chan struct{}),
+	}
+	go r.run(tot, workers)
+	return r
+}
+
+// Report records that worker has just finished rendering a page.
+func (r *progressReporter) Report(worker int) {
+	r.events <- progressEvent{worker: worker}
+}
+
+// Close stops the UI goroutine and waits for it to settle.
+func (r *progressReporter) Close() {
+	close(r.events)
+	<-r.done
+}
+
+func (r *progressReporter) run(tot, workers int) {
+	defer close(r.done)
+
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		r.runPlain(tot)
+		return
+	}
+
+	total := pb.New(tot).Set("prefix", "total")
+	bars := []*pb.ProgressBar{total}
+
+	var workerBars []*pb.ProgressBar
+	if workers > 1 {
+		workerBars = make([]*pb.ProgressBar, workers)
+		for i := range workerBars {
+			workerBars[i] = pb.New(0).Set("prefix", fmt.Sprintf("worker %d", i+1))
+			bars = append(bars, workerBars[i])
+		}
+	}
+
+	pool, err := pb.StartPool(bars...)
+	if err != nil {
+		// not all "is a terminal" checks agree with pb's own assumptions;
+		// fall back rather than fail the conversion over a UI nicety.
+		r.runPlain(tot)
+		return
+	}
+
+	for ev := range r.events {
+		total.Increment()
+		if workerBars != nil {
+			workerBars[ev.worker].Increment()
+		}
+	}
+
+	pool.Stop()
+}
+
+// runPlain mirrors the tool's original single-line updater, used whenever
+// stdout isn't a TTY (piped output, CI logs, etc).
+func (r *progressReporter) runPlain(tot int) {
+	const pollFreq = 5
+
+	cur := 0
+	for range r.events {
+		cur++
+		if cur%pollFreq == 0 {
+			logProgress(tot, cur)
+		}
+	}
+	logProgress(tot, cur)
+}
+
+func logProgress(tot int, cur int) {
+
+	const SYMBOL_WIDTH = 20
+	const SYMBOL = "#"
+
+	progress := float64(cur) / float64(tot)
+	outStr := fmt.Sprintf("\rthrough pg: %d\t[", cur)
+
+	symCnt := int(math.Ceil(SYMBOL_WIDTH * progress))
+	outStr += strings.Repeat(SYMBOL, symCnt)
+	outStr += strings.Repeat(" ", SYMBOL_WIDTH-symCnt) + "]"
+	outStr += string(" ") + fmt.Sprintf("%.1f%%", progress*100)
+	fmt.Printf("%s", outStr)
+}